@@ -0,0 +1,69 @@
+package coff
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/bitfocus/syso/pkg/common"
+)
+
+// bigObjClassID is the ANON_OBJECT_HEADER_BIGOBJ ClassID GUID
+// {D1BAA1C7-BAEE-4BA9-AF20-FAF66AA4DCB8}, encoded little-endian as it
+// appears on disk.
+var bigObjClassID = [16]byte{
+	0xc7, 0xa1, 0xba, 0xd1,
+	0xee, 0xba,
+	0xa9, 0x4b,
+	0xaf, 0x20, 0xfa, 0xf6, 0x6a, 0xa4, 0xdc, 0xb8,
+}
+
+// rawBigObjHeader is the on-disk ANON_OBJECT_HEADER_BIGOBJ layout MSVC's
+// link.exe falls back to once an object has more sections or relocations
+// than the classic IMAGE_FILE_HEADER can address.
+type rawBigObjHeader struct {
+	Sig1                 uint16
+	Sig2                 uint16
+	Version              uint16
+	Machine              uint16
+	TimeDateStamp        uint32
+	ClassID              [16]byte
+	SizeOfData           uint32
+	Flags                uint32
+	MetaDataSize         uint32
+	MetaDataOffset       uint32
+	NumberOfSections     uint32
+	PointerToSymbolTable uint32
+	NumberOfSymbols      uint32
+}
+
+// WriteBigObjTo writes f using the Microsoft ANON_OBJECT_HEADER_BIGOBJ
+// format instead of the classic COFF file header. It is otherwise
+// identical to WriteTo (same section table, relocations, symbol and
+// string tables) and is an opt-in alternative: callers that know their
+// .rsrc has grown past what the classic header can represent should use
+// this instead.
+func (f *File) WriteBigObjTo(w io.Writer) (int64, error) {
+	var written int64
+
+	f.freeze(uint32(binary.Size(&rawBigObjHeader{})))
+
+	machine, _ := f.coffMachine()
+	n, err := common.BinaryWriteTo(w, &rawBigObjHeader{
+		Sig1:                 0, // IMAGE_FILE_MACHINE_UNKNOWN
+		Sig2:                 0xffff,
+		Version:              2,
+		Machine:              machine,
+		ClassID:              bigObjClassID,
+		NumberOfSections:     uint32(len(f.sections)),
+		PointerToSymbolTable: f.symbolsOffset,
+		NumberOfSymbols:      f.symbolTableEntries(),
+	})
+	if err != nil {
+		return written, err
+	}
+	written += n
+
+	n, err = f.writeBody(w)
+	written += n
+	return written, err
+}
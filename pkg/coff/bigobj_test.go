@@ -0,0 +1,89 @@
+package coff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// testSection is a minimal Section used to exercise File without
+// depending on the rsrc package.
+type testSection struct {
+	name   string
+	data   []byte
+	relocs []Relocation
+}
+
+func (s *testSection) Name() string              { return s.name }
+func (s *testSection) Size() int                 { return len(s.data) }
+func (s *testSection) Relocations() []Relocation { return s.relocs }
+func (s *testSection) Characteristics() uint32   { return DefaultCharacteristics }
+func (s *testSection) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.data)
+	return int64(n), err
+}
+
+func newTestFile(t *testing.T) (*File, *testSection) {
+	t.Helper()
+	f := New()
+	sec := &testSection{name: ".test", data: []byte("hello, syso")}
+	if err := f.AddSection(sec); err != nil {
+		t.Fatalf("AddSection: %v", err)
+	}
+	if _, err := f.AddSectionSymbol(sec.Name(), sec); err != nil {
+		t.Fatalf("AddSectionSymbol: %v", err)
+	}
+	return f, sec
+}
+
+// TestWriteBigObjToOffsets guards against freeze() assuming the classic
+// 20-byte IMAGE_FILE_HEADER while WriteBigObjTo writes the larger
+// ANON_OBJECT_HEADER_BIGOBJ preamble: every PointerToRawData computed by
+// freeze() must actually point at the section's bytes in the BigObj
+// output.
+func TestWriteBigObjToOffsets(t *testing.T) {
+	f, sec := newTestFile(t)
+
+	var buf bytes.Buffer
+	if _, err := f.WriteBigObjTo(&buf); err != nil {
+		t.Fatalf("WriteBigObjTo: %v", err)
+	}
+
+	headerSize := uint32(binary.Size(&rawBigObjHeader{}))
+	wantDataOffset := headerSize + uint32(binary.Size(&rawSectionHeader{})*len(f.sections))
+	if f.sections[0].dataOffset != wantDataOffset {
+		t.Fatalf("dataOffset = %d, want %d", f.sections[0].dataOffset, wantDataOffset)
+	}
+
+	got := buf.Bytes()[wantDataOffset : wantDataOffset+uint32(len(sec.data))]
+	if !bytes.Equal(got, sec.data) {
+		t.Fatalf("section data at computed offset = %q, want %q", got, sec.data)
+	}
+}
+
+// TestWriteBigObjToHeader guards the two fixed fields every
+// ANON_OBJECT_HEADER_BIGOBJ consumer checks before trusting the rest of
+// the header: Sig1/Sig2 must read 0x0000/0xffff (link.exe, lld and
+// binutils all reject anything else as not BigObj), and NumberOfSymbols
+// must count every auxiliary record, not just one slot per Symbol.
+func TestWriteBigObjToHeader(t *testing.T) {
+	f, _ := newTestFile(t)
+	f.symbols[0].Aux = 2
+
+	var buf bytes.Buffer
+	if _, err := f.WriteBigObjTo(&buf); err != nil {
+		t.Fatalf("WriteBigObjTo: %v", err)
+	}
+
+	var hdr rawBigObjHeader
+	if err := binary.Read(bytes.NewReader(buf.Bytes()), binary.LittleEndian, &hdr); err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	if hdr.Sig1 != 0 || hdr.Sig2 != 0xffff {
+		t.Fatalf("Sig1/Sig2 = %#x/%#x, want 0x0/0xffff", hdr.Sig1, hdr.Sig2)
+	}
+	if want := f.symbolTableEntries(); hdr.NumberOfSymbols != want {
+		t.Fatalf("NumberOfSymbols = %d, want %d", hdr.NumberOfSymbols, want)
+	}
+}
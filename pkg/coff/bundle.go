@@ -0,0 +1,96 @@
+package coff
+
+import (
+	"fmt"
+	"os"
+)
+
+// DefaultArchs lists the architectures NewBundle targets when the
+// caller doesn't need to restrict the set, in the spelling SetArch
+// accepts.
+var DefaultArchs = []string{"i386", "amd64", "arm", "arm64"}
+
+// MultiArch bundles one *File per architecture, all built from the same
+// logical resources, and writes them out as the separate per-arch syso
+// files the Go build system picks up automatically. This mirrors how
+// debug/macho/fat.go bundles several Mach-O images into one fat binary,
+// except here each arch gets its own file rather than sharing one.
+type MultiArch struct {
+	files map[string]*File // keyed by File.Arch()
+}
+
+// NewMultiArch returns an empty bundle.
+func NewMultiArch() *MultiArch {
+	return &MultiArch{files: make(map[string]*File)}
+}
+
+// Add registers f under its own Arch(), replacing any file previously
+// registered for that architecture.
+func (m *MultiArch) Add(f *File) {
+	m.files[f.Arch()] = f
+}
+
+// File returns the bundle's File for arch, if any.
+func (m *MultiArch) File(arch string) (*File, bool) {
+	f, ok := m.files[arch]
+	return f, ok
+}
+
+// NewBundle builds a MultiArch containing one *File per requested
+// architecture, each carrying the same rsrc section. This is the
+// high-level entry point for the common case: build a single .rsrc
+// (icon, manifest, version info, ...) with the rsrc package, then hand
+// it here instead of hand-rolling the per-arch File/SetArch/AddSection
+// plumbing.
+func NewBundle(rsrcSection Section, archs ...string) (*MultiArch, error) {
+	if len(archs) == 0 {
+		archs = DefaultArchs
+	}
+
+	m := NewMultiArch()
+	for _, arch := range archs {
+		f := New()
+		if err := f.SetArch(arch); err != nil {
+			return nil, fmt.Errorf("coff: building bundle file for %q: %w", arch, err)
+		}
+		if err := f.AddSection(rsrcSection); err != nil {
+			return nil, fmt.Errorf("coff: adding .rsrc to %q file: %w", arch, err)
+		}
+		if _, err := f.AddSectionSymbol(rsrcSection.Name(), rsrcSection); err != nil {
+			return nil, fmt.Errorf("coff: registering %q symbol for %q: %w", rsrcSection.Name(), arch, err)
+		}
+		m.Add(f)
+	}
+	return m, nil
+}
+
+// goArch maps a coff.File arch to the GOARCH name the Go toolchain
+// expects in a _GOOS_GOARCH.syso filename suffix.
+func goArch(arch string) string {
+	if arch == "i386" {
+		return "386"
+	}
+	return arch
+}
+
+// WriteFiles writes one "<prefix>_windows_<goarch>.syso" per
+// architecture registered in the bundle into the current directory.
+func (m *MultiArch) WriteFiles(prefix string) error {
+	for arch, f := range m.files {
+		name := fmt.Sprintf("%s_windows_%s.syso", prefix, goArch(arch))
+		out, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("coff: creating %q: %w", name, err)
+		}
+
+		_, writeErr := f.WriteTo(out)
+		closeErr := out.Close()
+		if writeErr != nil {
+			return fmt.Errorf("coff: writing %q: %w", name, writeErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("coff: closing %q: %w", name, closeErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package coff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewBundleSetsArchPerFile guards against NewBundle handing every
+// per-arch File the same arch (e.g. by sharing state or skipping
+// SetArch): each File in the bundle must report back the arch it was
+// built for, and carry the shared rsrc section.
+func TestNewBundleSetsArchPerFile(t *testing.T) {
+	sec := &testSection{name: ".rsrc", data: []byte("resource data")}
+
+	m, err := NewBundle(sec, "amd64", "i386")
+	if err != nil {
+		t.Fatalf("NewBundle: %v", err)
+	}
+
+	for _, arch := range []string{"amd64", "i386"} {
+		f, ok := m.File(arch)
+		if !ok {
+			t.Fatalf("File(%q): not found", arch)
+		}
+		if f.Arch() != arch {
+			t.Fatalf("File(%q).Arch() = %q, want %q", arch, f.Arch(), arch)
+		}
+		if _, err := f.Section(sec.Name()); err != nil {
+			t.Fatalf("File(%q).Section(%q): %v", arch, sec.Name(), err)
+		}
+	}
+}
+
+// TestWriteFilesNamesPerArch guards the _windows_<goarch>.syso naming
+// convention the Go build system relies on to pick these files up,
+// including the i386->386 GOARCH rename.
+func TestWriteFilesNamesPerArch(t *testing.T) {
+	sec := &testSection{name: ".rsrc", data: []byte("resource data")}
+
+	m, err := NewBundle(sec, "amd64", "i386")
+	if err != nil {
+		t.Fatalf("NewBundle: %v", err)
+	}
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	if err := m.WriteFiles("rsrc"); err != nil {
+		t.Fatalf("WriteFiles: %v", err)
+	}
+
+	for _, name := range []string{"rsrc_windows_amd64.syso", "rsrc_windows_386.syso"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+}
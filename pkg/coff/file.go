@@ -32,6 +32,7 @@ type File struct {
 	arch            string
 	machineType     uint16
 	sections        []*section
+	symbols         []*Symbol
 	symbolsOffset   uint32
 	strings         []*_string
 	stringTable     map[string]*_string
@@ -46,6 +47,19 @@ const (
 	_IMAGE_REL_ARM_ADDR32NB   = 0x02
 )
 
+// IMAGE_FILE_MACHINE_* constants, used in rawFileHeader.Machine.
+const (
+	_IMAGE_FILE_MACHINE_I386  = 0x14c
+	_IMAGE_FILE_MACHINE_ARM   = 0x1c0
+	_IMAGE_FILE_MACHINE_ARMNT = 0x1c4
+	_IMAGE_FILE_MACHINE_AMD64 = 0x8664
+	_IMAGE_FILE_MACHINE_ARM64 = 0xaa64
+)
+
+// _IMAGE_FILE_32BIT_MACHINE marks the object as targeting a 32-bit machine.
+// It must not be set for 64-bit architectures.
+const _IMAGE_FILE_32BIT_MACHINE = 0x0100
+
 // New returns newly created COFF file.
 func New() *File {
 	return &File{
@@ -66,6 +80,9 @@ func (f *File) SetArch(architechture string) error {
 	} else if architechture == "arm64" {
 		f.arch = "arm64"
 		f.machineType = _IMAGE_REL_ARM64_ADDR32NB
+	} else if architechture == "arm" {
+		f.arch = "arm"
+		f.machineType = _IMAGE_REL_ARM_ADDR32NB
 	} else if architechture == "i386" {
 		f.arch = "i386"
 		f.machineType = _IMAGE_REL_I386_DIR32NB
@@ -75,6 +92,21 @@ func (f *File) SetArch(architechture string) error {
 	return nil
 }
 
+// coffMachine returns the IMAGE_FILE_MACHINE_* value and the
+// IMAGE_FILE_32BIT_MACHINE characteristic bit (if any) for f.arch.
+func (f *File) coffMachine() (machine uint16, characteristics uint16) {
+	switch f.arch {
+	case "amd64":
+		return _IMAGE_FILE_MACHINE_AMD64, 0
+	case "arm64":
+		return _IMAGE_FILE_MACHINE_ARM64, 0
+	case "arm":
+		return _IMAGE_FILE_MACHINE_ARMNT, 0
+	default: // "i386"
+		return _IMAGE_FILE_MACHINE_I386, _IMAGE_FILE_32BIT_MACHINE
+	}
+}
+
 // AddSection adds section s to file.
 func (f *File) AddSection(s Section) error {
 	for _, sec := range f.sections {
@@ -86,17 +118,26 @@ func (f *File) AddSection(s Section) error {
 		Section: s,
 	})
 	if len(s.Name()) > 8 {
-		if _, ok := f.stringTable[s.Name()]; !ok {
-			str := &_string{
-				b: []byte(s.Name() + "\x00"), // null-terminated UTF-8 encoded string
-			}
-			f.strings = append(f.strings, str)
-			f.stringTable[s.Name()] = str
-		}
+		f.addLongName(s.Name())
 	}
 	return nil
 }
 
+// addLongName registers name in f's string table, if it isn't already
+// there. Section and symbol names longer than 8 bytes are stored here
+// rather than inline in their raw header/record, which only has room
+// for an 8-byte name or a "/<offset>" reference into this table.
+func (f *File) addLongName(name string) {
+	if _, ok := f.stringTable[name]; ok {
+		return
+	}
+	str := &_string{
+		b: []byte(name + "\x00"), // null-terminated UTF-8 encoded string
+	}
+	f.strings = append(f.strings, str)
+	f.stringTable[name] = str
+}
+
 // Section finds a section by its name and returns it if found.
 func (f *File) Section(name string) (Section, error) {
 	for _, s := range f.sections {
@@ -107,8 +148,38 @@ func (f *File) Section(name string) (Section, error) {
 	return nil, ErrSectionNotFound
 }
 
-func (f *File) freeze() {
-	offset := uint32(binary.Size(&rawFileHeader{}))
+// _IMAGE_SCN_LNK_NRELOC_OVFL marks a section whose true relocation count
+// doesn't fit in the 16-bit NumberOfRelocations field; the real count is
+// then stored in the VirtualAddress of a synthetic first relocation entry.
+const _IMAGE_SCN_LNK_NRELOC_OVFL = 0x01000000
+
+// relocSlotCount returns the number of rawRelocation slots needed to
+// represent relocs on disk, including the synthetic count entry
+// required once the 16-bit NumberOfRelocations field overflows.
+func relocSlotCount(relocs []Relocation) int {
+	if len(relocs) > 0xffff {
+		return len(relocs) + 1
+	}
+	return len(relocs)
+}
+
+// symbolTableEntries returns the number of 18-byte symbol-table slots
+// f.symbols occupies, counting each symbol's auxiliary records alongside
+// its own primary entry.
+func (f *File) symbolTableEntries() uint32 {
+	var n uint32
+	for _, sym := range f.symbols {
+		n += 1 + uint32(sym.Aux)
+	}
+	return n
+}
+
+// freeze lays out the section data, relocations, symbol table and
+// string table following a header of headerSize bytes, recording each
+// section's dataOffset/relocationsOffset and f.symbolsOffset so they
+// line up with whichever header WriteTo/WriteBigObjTo actually writes.
+func (f *File) freeze(headerSize uint32) {
+	offset := headerSize
 	offset += uint32(binary.Size(&rawSectionHeader{}) * len(f.sections))
 	for _, s := range f.sections {
 		s.dataOffset = offset
@@ -116,37 +187,51 @@ func (f *File) freeze() {
 	}
 	for _, s := range f.sections {
 		s.relocationsOffset = offset
-		offset += uint32(binary.Size(&rawRelocation{}) * len(s.Relocations()))
+		offset += uint32(binary.Size(&rawRelocation{}) * relocSlotCount(s.Relocations()))
 	}
 	f.symbolsOffset = offset
-	offset += uint32(binary.Size(&rawSymbol{}) * len(f.sections))
-	offset += 4  // string table size
-	so := offset // start offset of string table
+	offset += uint32(binary.Size(&rawSymbol{})) * f.symbolTableEntries()
+
+	// Long-name references ("/<n>") are offsets from the start of the
+	// string table itself, not absolute file offsets; the table's own
+	// 4-byte size field occupies the first 4 bytes of that range.
+	strOffset := uint32(4)
 	for _, s := range f.strings {
-		s.offset = offset
-		offset += uint32(len(s.b))
+		s.offset = strOffset
+		strOffset += uint32(len(s.b))
 	}
-	f.stringTableSize = offset - so + 4
+	f.stringTableSize = strOffset
 }
 
 // WriteTo writes COFF file data to w.
 func (f *File) WriteTo(w io.Writer) (int64, error) {
 	var written int64
 
-	f.freeze()
+	f.freeze(uint32(binary.Size(&rawFileHeader{})))
 
+	machine, characteristics := f.coffMachine()
 	n, err := common.BinaryWriteTo(w, &rawFileHeader{
-		Machine:              0x14c, // IMAGE_FILE_MACHINE_I386
+		Machine:              machine,
 		NumberOfSections:     uint16(len(f.sections)),
 		PointerToSymbolTable: f.symbolsOffset,
-		NumberOfSymbols:      uint32(len(f.sections)),
-		Characteristics:      0x0100, // IMAGE_FILE_32BIT_MACHINE
+		NumberOfSymbols:      f.symbolTableEntries(),
+		Characteristics:      characteristics,
 	})
 	if err != nil {
 		return written, err
 	}
 	written += n
 
+	n, err = f.writeBody(w)
+	written += n
+	return written, err
+}
+
+// writeBody writes the section table, section data, relocations, symbol
+// table and string table shared by the classic and BigObj file headers.
+func (f *File) writeBody(w io.Writer) (int64, error) {
+	var written int64
+
 	for _, s := range f.sections {
 		var name [8]byte
 		if len(s.Name()) > 8 {
@@ -154,13 +239,19 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 		} else {
 			copy(name[:], s.Name())
 		}
+		characteristics := s.Characteristics()
+		nRelocs := len(s.Relocations())
+		if nRelocs > 0xffff {
+			characteristics |= _IMAGE_SCN_LNK_NRELOC_OVFL
+			nRelocs = 0xffff
+		}
 		n, err := common.BinaryWriteTo(w, &rawSectionHeader{
 			Name:                 name,
 			SizeOfRawData:        uint32(s.Size()),
 			PointerToRawData:     s.dataOffset,
 			PointerToRelocations: s.relocationsOffset,
-			NumberOfRelocations:  uint16(len(s.Relocations())),
-			Characteristics:      0x40000040, // IMAGE_SCN_MEM_READ|IMAGE_SCN_CNT_INITIALIZED_DATA
+			NumberOfRelocations:  uint16(nRelocs),
+			Characteristics:      characteristics,
 		})
 		if err != nil {
 			return written, err
@@ -176,11 +267,31 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 		written += n
 	}
 
-	for i, s := range f.sections {
-		for _, r := range s.Relocations() {
+	for _, s := range f.sections {
+		relocs := s.Relocations()
+		if len(relocs) > 0xffff {
+			n, err := common.BinaryWriteTo(w, &rawRelocation{
+				VirtualAddress: uint32(len(relocs) + 1),
+			})
+			if err != nil {
+				return written, err
+			}
+			written += n
+		}
+		for _, r := range relocs {
+			var symbolIndex uint32
+			if ir, ok := r.(indexedRelocation); ok {
+				symbolIndex = ir.symbolTableIndex()
+			} else {
+				var err error
+				symbolIndex, err = f.symbolIndex(r.SymbolName())
+				if err != nil {
+					return written, err
+				}
+			}
 			n, err := common.BinaryWriteTo(w, &rawRelocation{
 				VirtualAddress:   r.VirtualAddress(),
-				SymbolTableIndex: uint32(i),
+				SymbolTableIndex: symbolIndex,
 				Type:             f.machineType, // IMAGE_REL_I386_DIR32NB, etc..
 			})
 			if err != nil {
@@ -190,25 +301,36 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
-	for i, s := range f.sections {
+	for _, sym := range f.symbols {
 		var name [8]byte
-		if len(s.Name()) > 8 {
-			binary.LittleEndian.PutUint32(name[4:], f.stringTable[s.Name()].offset)
+		if len(sym.Name) > 8 {
+			binary.LittleEndian.PutUint32(name[4:], f.stringTable[sym.Name].offset)
 		} else {
-			copy(name[:], s.Name())
+			copy(name[:], sym.Name)
 		}
 		n, err := common.BinaryWriteTo(w, &rawSymbol{
-			Name:          name,
-			SectionNumber: uint16(i) + 1,
-			StorageClass:  3, // IMAGE_SYM_CLASS_STATIC
+			Name:               name,
+			Value:              sym.Value,
+			SectionNumber:      uint16(sym.SectionNumber),
+			Type:               sym.Type,
+			StorageClass:       sym.StorageClass,
+			NumberOfAuxSymbols: sym.Aux,
 		})
 		if err != nil {
 			return written, err
 		}
 		written += n
+
+		if len(sym.AuxData) > 0 {
+			auxN, err := w.Write(sym.AuxData)
+			if err != nil {
+				return written, err
+			}
+			written += int64(auxN)
+		}
 	}
 
-	n, err = common.BinaryWriteTo(w, f.stringTableSize)
+	n, err := common.BinaryWriteTo(w, f.stringTableSize)
 	if err != nil {
 		return written, err
 	}
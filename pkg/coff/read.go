@@ -0,0 +1,170 @@
+package coff
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// rawSection is a Section whose bytes and relocations were parsed
+// verbatim from an existing object file, as produced by Read. It lets a
+// parsed file be modified (via AddSection/Section) and re-emitted
+// unchanged by WriteTo.
+type rawSection struct {
+	name            string
+	data            []byte
+	relocations     []Relocation
+	characteristics uint32
+}
+
+func (s *rawSection) Name() string              { return s.name }
+func (s *rawSection) Size() int                 { return len(s.data) }
+func (s *rawSection) Relocations() []Relocation { return s.relocations }
+func (s *rawSection) Characteristics() uint32   { return s.characteristics }
+
+func (s *rawSection) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.data)
+	return int64(n), err
+}
+
+// rawRelocationEntry is a Relocation parsed verbatim from an existing
+// object file. It carries its original symbol-table index, not just the
+// symbol's name: Read preserves every symbol an object declares,
+// including ones that share a name with another (e.g. repeated "$end"
+// markers), so the index is the only reliable way to retarget it at the
+// right one.
+type rawRelocationEntry struct {
+	va         uint32
+	symbolName string
+	symbolIdx  uint32
+}
+
+func (r *rawRelocationEntry) VirtualAddress() uint32   { return r.va }
+func (r *rawRelocationEntry) SymbolName() string       { return r.symbolName }
+func (r *rawRelocationEntry) symbolTableIndex() uint32 { return r.symbolIdx }
+
+// archForMachine maps an IMAGE_FILE_MACHINE_* value back to the arch
+// string accepted by SetArch.
+func archForMachine(machine uint16) string {
+	switch machine {
+	case _IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case _IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case _IMAGE_FILE_MACHINE_ARM, _IMAGE_FILE_MACHINE_ARMNT:
+		return "arm"
+	default:
+		return "i386"
+	}
+}
+
+// Read parses an existing COFF object (such as one produced by WriteTo)
+// from r, reusing debug/pe for the header, section table, relocation and
+// symbol/string table parsing. The returned File can be inspected,
+// modified with AddSection, and re-emitted with WriteTo.
+func Read(r io.ReaderAt) (*File, error) {
+	pf, err := pe.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("coff: parsing COFF header: %w", err)
+	}
+
+	f := New()
+	if err := f.SetArch(archForMachine(pf.Machine)); err != nil {
+		return nil, err
+	}
+
+	symbolNames, err := readSymbolNames(pf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sec := range pf.Sections {
+		data, err := sec.Data()
+		if err != nil {
+			return nil, fmt.Errorf("coff: reading section %q data: %w", sec.Name, err)
+		}
+
+		relocations := make([]Relocation, 0, len(sec.Relocs))
+		for _, reloc := range sec.Relocs {
+			if int(reloc.SymbolTableIndex) >= len(symbolNames) {
+				return nil, fmt.Errorf("coff: section %q relocation references out-of-range symbol %d", sec.Name, reloc.SymbolTableIndex)
+			}
+			relocations = append(relocations, &rawRelocationEntry{
+				va:         reloc.VirtualAddress,
+				symbolName: symbolNames[reloc.SymbolTableIndex],
+				symbolIdx:  reloc.SymbolTableIndex,
+			})
+		}
+
+		if err := f.AddSection(&rawSection{
+			name:            sec.Name,
+			data:            data,
+			relocations:     relocations,
+			characteristics: sec.Characteristics,
+		}); err != nil {
+			return nil, fmt.Errorf("coff: adding section %q: %w", sec.Name, err)
+		}
+	}
+
+	for i := 0; i < len(pf.COFFSymbols); i++ {
+		sym := pf.COFFSymbols[i]
+
+		var auxData []byte
+		for a := 0; a < int(sym.NumberOfAuxSymbols); a++ {
+			aux, err := encodeAuxSymbol(pf.COFFSymbols[i+1+a])
+			if err != nil {
+				return nil, fmt.Errorf("coff: encoding aux record %d of symbol %q: %w", a, symbolNames[i], err)
+			}
+			auxData = append(auxData, aux...)
+		}
+
+		// addSymbolRaw, not AddSymbol: objects legitimately reuse a
+		// symbol name across sections (e.g. "$end" markers), and
+		// relocations above were already resolved to their original
+		// symbol-table index rather than by name, so there's no
+		// need to drop the later registrations the way AddSymbol's
+		// deduplication would (which could previously misdirect a
+		// relocation at the wrong same-named symbol).
+		f.addSymbolRaw(&Symbol{
+			Name:          symbolNames[i],
+			Value:         sym.Value,
+			SectionNumber: sym.SectionNumber,
+			Type:          sym.Type,
+			StorageClass:  sym.StorageClass,
+			Aux:           sym.NumberOfAuxSymbols,
+			AuxData:       auxData,
+		})
+		i += int(sym.NumberOfAuxSymbols)
+	}
+
+	return f, nil
+}
+
+// encodeAuxSymbol re-encodes an auxiliary COFF symbol-table entry back
+// into its raw 18-byte on-disk form. debug/pe exposes auxiliary records
+// as pe.COFFSymbol values using the same layout as a primary symbol
+// rather than handing back their original bytes, so this reconstructs
+// them instead of carrying them through opaquely.
+func encodeAuxSymbol(sym pe.COFFSymbol) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, sym); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readSymbolNames resolves every raw COFF symbol (including auxiliary
+// records, so indices line up with Reloc.SymbolTableIndex) to its name.
+func readSymbolNames(pf *pe.File) ([]string, error) {
+	names := make([]string, len(pf.COFFSymbols))
+	for i, sym := range pf.COFFSymbols {
+		name, err := sym.FullName(pf.StringTable)
+		if err != nil {
+			return nil, fmt.Errorf("coff: resolving symbol %d name: %w", i, err)
+		}
+		names[i] = name
+	}
+	return names, nil
+}
@@ -0,0 +1,65 @@
+package coff
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testNamedRelocation is a Relocation resolved purely by name, the way
+// a caller assembling a File from scratch (rather than via Read)
+// builds one.
+type testNamedRelocation struct {
+	va   uint32
+	name string
+}
+
+func (r *testNamedRelocation) VirtualAddress() uint32 { return r.va }
+func (r *testNamedRelocation) SymbolName() string     { return r.name }
+
+// TestReadRoundTrip guards coff.Read against the file File.WriteTo
+// produces: a section's name, data and characteristics, its symbol, and
+// a relocation pointing at a second, external symbol must all survive a
+// WriteTo/Read round trip unchanged.
+func TestReadRoundTrip(t *testing.T) {
+	f := New()
+	sec := &testSection{name: ".test", data: []byte("hello, syso")}
+	if err := f.AddSection(sec); err != nil {
+		t.Fatalf("AddSection: %v", err)
+	}
+	if _, err := f.AddSectionSymbol(sec.Name(), sec); err != nil {
+		t.Fatalf("AddSectionSymbol: %v", err)
+	}
+	if _, err := f.AddExternalSymbol("_imported"); err != nil {
+		t.Fatalf("AddExternalSymbol: %v", err)
+	}
+	sec.relocs = []Relocation{&testNamedRelocation{va: 0, name: "_imported"}}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	got, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	rs, err := got.Section(".test")
+	if err != nil {
+		t.Fatalf("Section(%q): %v", ".test", err)
+	}
+	if rs.Size() != len(sec.data) {
+		t.Fatalf("Section size = %d, want %d", rs.Size(), len(sec.data))
+	}
+	if rs.Characteristics() != DefaultCharacteristics {
+		t.Fatalf("Section characteristics = %#x, want %#x", rs.Characteristics(), DefaultCharacteristics)
+	}
+
+	relocs := rs.Relocations()
+	if len(relocs) != 1 {
+		t.Fatalf("got %d relocations, want 1", len(relocs))
+	}
+	if relocs[0].SymbolName() != "_imported" {
+		t.Fatalf("relocation symbol = %q, want %q", relocs[0].SymbolName(), "_imported")
+	}
+}
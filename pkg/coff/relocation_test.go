@@ -0,0 +1,53 @@
+package coff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// testIndexedRelocation is a Relocation that already knows its target
+// symbol-table index, the way Read's relocations do.
+type testIndexedRelocation struct {
+	va  uint32
+	idx uint32
+}
+
+func (r *testIndexedRelocation) VirtualAddress() uint32   { return r.va }
+func (r *testIndexedRelocation) SymbolName() string       { return "dup" }
+func (r *testIndexedRelocation) symbolTableIndex() uint32 { return r.idx }
+
+// TestIndexedRelocationBypassesNameLookup guards against relocations
+// being misdirected when two symbols share a name: resolving "dup" by
+// name alone would always land on the first registration, but a
+// relocation that already knows its symbol-table index (as Read's do)
+// must be written against that index instead.
+func TestIndexedRelocationBypassesNameLookup(t *testing.T) {
+	f := New()
+	sec := &testSection{name: ".test", data: []byte("data")}
+	sec2 := &testSection{name: ".test2", data: []byte("more")}
+
+	if err := f.AddSection(sec); err != nil {
+		t.Fatalf("AddSection: %v", err)
+	}
+	if err := f.AddSection(sec2); err != nil {
+		t.Fatalf("AddSection: %v", err)
+	}
+	f.addSymbolRaw(&Symbol{Name: "dup", SectionNumber: 1, StorageClass: _IMAGE_SYM_CLASS_STATIC})
+	f.addSymbolRaw(&Symbol{Name: "dup", SectionNumber: 2, StorageClass: _IMAGE_SYM_CLASS_STATIC})
+	sec.relocs = []Relocation{&testIndexedRelocation{va: 0, idx: 1}}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	var raw rawRelocation
+	r := bytes.NewReader(buf.Bytes()[f.sections[0].relocationsOffset:])
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		t.Fatalf("decoding relocation: %v", err)
+	}
+	if raw.SymbolTableIndex != 1 {
+		t.Fatalf("SymbolTableIndex = %d, want 1 (the index reloc.symbolTableIndex() named, not the first \"dup\")", raw.SymbolTableIndex)
+	}
+}
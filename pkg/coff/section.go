@@ -0,0 +1,54 @@
+package coff
+
+import "io"
+
+// Relocation describes where in a Section's data a fixup must be
+// applied and which symbol it targets; File.WriteTo resolves SymbolName
+// to that symbol's table index to produce a concrete IMAGE_RELOCATION
+// entry. The target symbol must have been registered with the File the
+// relocation's section is added to, via AddSymbol, AddSectionSymbol or
+// AddExternalSymbol.
+type Relocation interface {
+	VirtualAddress() uint32
+	SymbolName() string
+}
+
+// indexedRelocation is implemented by relocations that already know the
+// symbol-table index they target, bypassing the by-name lookup
+// File.writeBody otherwise falls back to. Read's relocations implement
+// this: resolving them by name alone would misdirect a relocation if its
+// original symbol shares a name with another symbol in the file (for
+// example repeated "$end" markers), since a by-name lookup always lands
+// on the first of them.
+type indexedRelocation interface {
+	symbolTableIndex() uint32
+}
+
+// DefaultCharacteristics is IMAGE_SCN_MEM_READ|IMAGE_SCN_CNT_INITIALIZED_DATA,
+// the characteristics every section was hard-coded to before sections
+// could report their own.
+const DefaultCharacteristics = 0x40000040
+
+// Section is anything that can contribute a named section, such as
+// .rsrc or .drectve, to a COFF file.
+type Section interface {
+	// Name returns the section name, e.g. ".rsrc".
+	Name() string
+	// Size returns the size in bytes of the data WriteTo will produce.
+	Size() int
+	// Relocations returns the relocations that apply to the section.
+	Relocations() []Relocation
+	// Characteristics returns the IMAGE_SCN_* flags the section should
+	// be written with. DefaultCharacteristics fits read-only
+	// initialized data such as .rsrc.
+	Characteristics() uint32
+	io.WriterTo
+}
+
+// section pairs a Section with the file-relative offsets File.freeze
+// computes for it.
+type section struct {
+	Section
+	dataOffset        uint32
+	relocationsOffset uint32
+}
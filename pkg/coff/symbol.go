@@ -0,0 +1,107 @@
+package coff
+
+import "fmt"
+
+// IMAGE_SYM_CLASS_* storage classes, used in Symbol.StorageClass.
+const (
+	_IMAGE_SYM_CLASS_EXTERNAL = 2
+	_IMAGE_SYM_CLASS_STATIC   = 3
+)
+
+// Symbol is a COFF symbol-table entry. A Relocation targets one by
+// name; File assigns the final symbol-table index and resolves that
+// reference when the file is written.
+type Symbol struct {
+	Name          string
+	Value         uint32
+	SectionNumber int16
+	Type          uint16
+	StorageClass  uint8
+	Aux           uint8  // number of auxiliary symbol-table records following this one
+	AuxData       []byte // raw auxiliary records, Aux*18 bytes, written verbatim after this entry
+}
+
+// ErrSymbolExists is returned by AddSymbol when a symbol with the given
+// name has already been registered.
+var ErrSymbolExists = fmt.Errorf("symbol with given name already exists")
+
+// AddSymbol registers sym in f's symbol table. Relocations targeting
+// sym.Name, on any section added to f, resolve against it when f is
+// written.
+func (f *File) AddSymbol(sym *Symbol) (*Symbol, error) {
+	for _, existing := range f.symbols {
+		if existing.Name == sym.Name {
+			return nil, ErrSymbolExists
+		}
+	}
+	f.addSymbolRaw(sym)
+	return sym, nil
+}
+
+// addSymbolRaw appends sym to f's symbol table without checking for a
+// name collision, returning its position in f.symbols (not its on-disk
+// symbol-table slot, which also counts earlier aux records; see
+// symbolIndex). Read uses this
+// directly to preserve symbols an object legitimately declares more than
+// once (e.g. repeated "$end" markers), which AddSymbol's deduplication
+// would otherwise drop; its relocations are retargeted by table index
+// rather than by name, so the collision is harmless there.
+func (f *File) addSymbolRaw(sym *Symbol) int {
+	f.symbols = append(f.symbols, sym)
+	if len(sym.Name) > 8 {
+		f.addLongName(sym.Name)
+	}
+	return len(f.symbols) - 1
+}
+
+// AddExternalSymbol registers an external (undefined) symbol named
+// name, for relocations the linker should resolve against another
+// object file or import library rather than anything in f.
+func (f *File) AddExternalSymbol(name string) (*Symbol, error) {
+	return f.AddSymbol(&Symbol{
+		Name:         name,
+		StorageClass: _IMAGE_SYM_CLASS_EXTERNAL,
+	})
+}
+
+// AddSectionSymbol registers the IMAGE_SYM_CLASS_STATIC symbol that
+// relocations pointing into s (which must already have been added to f
+// via AddSection) should target. name is conventionally s.Name().
+func (f *File) AddSectionSymbol(name string, s Section) (*Symbol, error) {
+	n, err := f.sectionNumber(s)
+	if err != nil {
+		return nil, err
+	}
+	return f.AddSymbol(&Symbol{
+		Name:          name,
+		SectionNumber: n,
+		StorageClass:  _IMAGE_SYM_CLASS_STATIC,
+	})
+}
+
+// sectionNumber returns the 1-based section number s was added under.
+func (f *File) sectionNumber(s Section) (int16, error) {
+	for i, sec := range f.sections {
+		if sec.Section == s {
+			return int16(i) + 1, nil
+		}
+	}
+	return 0, ErrSectionNotFound
+}
+
+// symbolIndex returns the on-disk symbol-table slot of the registered
+// symbol named name, accounting for every earlier symbol's auxiliary
+// records (each occupies 1+Aux slots, not one). It is the fallback
+// writeBody uses for relocations that don't already know their target
+// index (see indexedRelocation); if two symbols share a name, it
+// resolves to whichever was registered first.
+func (f *File) symbolIndex(name string) (uint32, error) {
+	var slot uint32
+	for _, sym := range f.symbols {
+		if sym.Name == name {
+			return slot, nil
+		}
+		slot += 1 + uint32(sym.Aux)
+	}
+	return 0, fmt.Errorf("coff: relocation references unknown symbol %q", name)
+}
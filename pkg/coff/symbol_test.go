@@ -0,0 +1,60 @@
+package coff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// TestSymbolTableEntriesCountsAux guards against Aux being sized for in
+// freeze() but not actually written: NumberOfSymbols (and the offsets
+// that follow the symbol table) must include every auxiliary record,
+// and those bytes must appear verbatim in the output.
+func TestSymbolTableEntriesCountsAux(t *testing.T) {
+	f := New()
+	aux := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18}
+	if _, err := f.AddSymbol(&Symbol{Name: "withaux", Aux: 1, AuxData: aux}); err != nil {
+		t.Fatalf("AddSymbol: %v", err)
+	}
+	if _, err := f.AddSymbol(&Symbol{Name: "plain"}); err != nil {
+		t.Fatalf("AddSymbol: %v", err)
+	}
+
+	if got, want := f.symbolTableEntries(), uint32(3); got != want {
+		t.Fatalf("symbolTableEntries() = %d, want %d", got, want)
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	symSize := uint32(binary.Size(&rawSymbol{}))
+	auxOffset := f.symbolsOffset + symSize
+	got := buf.Bytes()[auxOffset : auxOffset+uint32(len(aux))]
+	if !bytes.Equal(got, aux) {
+		t.Fatalf("aux record at computed offset = %v, want %v", got, aux)
+	}
+}
+
+// TestSymbolIndexAccountsForAux guards against symbolIndex treating an
+// aux-bearing symbol as a single table slot: writeBody's by-name
+// relocation fallback must land on the correct on-disk slot even when
+// an earlier symbol's aux records shift everything after it.
+func TestSymbolIndexAccountsForAux(t *testing.T) {
+	f := New()
+	if _, err := f.AddSymbol(&Symbol{Name: "withaux", Aux: 2}); err != nil {
+		t.Fatalf("AddSymbol: %v", err)
+	}
+	if _, err := f.AddSymbol(&Symbol{Name: "target"}); err != nil {
+		t.Fatalf("AddSymbol: %v", err)
+	}
+
+	got, err := f.symbolIndex("target")
+	if err != nil {
+		t.Fatalf("symbolIndex: %v", err)
+	}
+	if want := uint32(3); got != want {
+		t.Fatalf("symbolIndex(%q) = %d, want %d", "target", got, want)
+	}
+}
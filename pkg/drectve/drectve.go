@@ -0,0 +1,79 @@
+// Package drectve provides a COFF ".drectve" section carrying ASCII
+// linker directives, the same mechanism MSVC-compiled objects use to
+// pull in import libraries and set linker flags without post-processing
+// the produced binary.
+package drectve
+
+import (
+	"io"
+	"strings"
+
+	"github.com/bitfocus/syso/pkg/coff"
+)
+
+// Characteristics is IMAGE_SCN_ALIGN_1BYTES|IMAGE_SCN_LNK_REMOVE|
+// IMAGE_SCN_LNK_INFO (0x00100000|0x800|0x200): the section is linker
+// metadata only and must not end up in the final image.
+const Characteristics = 0x00100A00
+
+// Section is a .drectve section holding a list of linker directives
+// such as `/DEFAULTLIB:"user32"`, `/EXPORT:Foo`,
+// `/MANIFESTDEPENDENCY:...` or `/SUBSYSTEM:WINDOWS`.
+type Section struct {
+	directives []string
+}
+
+// New returns an empty .drectve section.
+func New() *Section {
+	return &Section{}
+}
+
+// Name returns the section's name, ".drectve".
+func (s *Section) Name() string {
+	return ".drectve"
+}
+
+// Characteristics returns drectve.Characteristics.
+func (s *Section) Characteristics() uint32 {
+	return Characteristics
+}
+
+// Add appends a linker directive, verbatim, to the section.
+func (s *Section) Add(directive string) {
+	s.directives = append(s.directives, directive)
+}
+
+// AddDefaultLib appends a `/DEFAULTLIB:"name"` directive, statically
+// pulling in the given import library.
+func (s *Section) AddDefaultLib(name string) {
+	s.Add(`/DEFAULTLIB:"` + name + `"`)
+}
+
+// AddExport appends a `/EXPORT:symbol` directive.
+func (s *Section) AddExport(symbol string) {
+	s.Add("/EXPORT:" + symbol)
+}
+
+func (s *Section) body() string {
+	if len(s.directives) == 0 {
+		return ""
+	}
+	return strings.Join(s.directives, " ") + " "
+}
+
+// Size returns the section's size.
+func (s *Section) Size() int {
+	return len(s.body())
+}
+
+// Relocations returns nil: directive text is plain ASCII and isn't
+// subject to relocation.
+func (s *Section) Relocations() []coff.Relocation {
+	return nil
+}
+
+// WriteTo writes the section's directive string to w.
+func (s *Section) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, s.body())
+	return int64(n), err
+}
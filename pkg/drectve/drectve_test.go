@@ -0,0 +1,41 @@
+package drectve
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSectionWriteTo guards the .drectve wire format: space-joined
+// directives followed by a trailing space, with Size reporting exactly
+// what WriteTo produces.
+func TestSectionWriteTo(t *testing.T) {
+	s := New()
+	s.AddDefaultLib("user32")
+	s.AddExport("Foo")
+
+	want := `/DEFAULTLIB:"user32" /EXPORT:Foo `
+	if got := s.Size(); got != len(want) {
+		t.Fatalf("Size() = %d, want %d", got, len(want))
+	}
+
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if int(n) != len(want) {
+		t.Fatalf("WriteTo wrote %d bytes, want %d", n, len(want))
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("WriteTo wrote %q, want %q", got, want)
+	}
+}
+
+// TestSectionEmpty guards the zero-directive case: body() short-circuits
+// to "" rather than joining an empty slice into a lone trailing space.
+func TestSectionEmpty(t *testing.T) {
+	s := New()
+	if got := s.Size(); got != 0 {
+		t.Fatalf("Size() = %d, want 0", got)
+	}
+}
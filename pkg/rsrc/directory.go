@@ -0,0 +1,190 @@
+package rsrc
+
+import (
+	"github.com/bitfocus/syso/pkg/common"
+	"github.com/pkg/errors"
+)
+
+// enUSLanguage is the Windows LANGID for US English
+// (MAKELANGID(LANG_ENGLISH, SUBLANG_ENGLISH_US)), the language every
+// AddResourceByID/AddResourceByName resource is registered under.
+const enUSLanguage = 0x0409
+
+// rawDirectory is the on-disk IMAGE_RESOURCE_DIRECTORY layout.
+type rawDirectory struct {
+	Characteristics     uint32
+	TimeDateStamp       uint32
+	MajorVersion        uint16
+	MinorVersion        uint16
+	NumberOfNameEntries uint16
+	NumberOfIDEntries   uint16
+}
+
+// rawDirectoryEntry is the on-disk IMAGE_RESOURCE_DIRECTORY_ENTRY layout.
+type rawDirectoryEntry struct {
+	NameOffsetOrIntegerID               uint32
+	DataEntryOffsetOrSubdirectoryOffset uint32
+}
+
+// rawDataEntry is the on-disk IMAGE_RESOURCE_DATA_ENTRY layout.
+type rawDataEntry struct {
+	DataRVA  uint32
+	Size     uint32
+	Codepage uint32
+	Reserved uint32
+}
+
+// _string is a UTF-16-on-disk resource name, referenced by a
+// directoryEntry whose NameOffsetOrIntegerID is a string-table offset
+// rather than an integer ID.
+type _string struct {
+	string string
+	offset uint32
+}
+
+// resourceBlob pairs a caller-supplied common.Blob with the file offset
+// freeze() assigns its raw bytes, so WriteTo can both point a
+// rawDataEntry at it and copy it out as the section's data.
+type resourceBlob struct {
+	common.Blob
+	offset uint32
+}
+
+// DataEntry is the leaf of the resource directory tree: one resource's
+// data, along with the file offset of its own IMAGE_RESOURCE_DATA_ENTRY
+// record. Section.addResource returns it for the resource it just
+// added; AddResourceByID and AddResourceByName return only an error.
+type DataEntry struct {
+	offset uint32
+	data   *resourceBlob
+}
+
+// directoryEntry is one IMAGE_RESOURCE_DIRECTORY_ENTRY: either a name
+// or an integer id, pointing at either a nested Directory or a leaf
+// DataEntry.
+type directoryEntry struct {
+	name         *_string
+	id           *int
+	offset       uint32
+	subdirectory *Directory
+	dataEntry    *DataEntry
+}
+
+// Directory is one level of the .rsrc resource tree (Type, Name/ID, or
+// Language, depending on depth).
+type Directory struct {
+	offset          uint32
+	characteristics uint32
+	nameEntries     []*directoryEntry
+	idEntries       []*directoryEntry
+	strings         []*_string
+}
+
+// entries returns the directory's entries in the order they're written
+// and read back in: every name entry, then every id entry.
+func (d *Directory) entries() []*directoryEntry {
+	out := make([]*directoryEntry, 0, len(d.nameEntries)+len(d.idEntries))
+	out = append(out, d.nameEntries...)
+	out = append(out, d.idEntries...)
+	return out
+}
+
+// dataEntries returns this directory's own leaf entries (not those of
+// its subdirectories).
+func (d *Directory) dataEntries() []*DataEntry {
+	var out []*DataEntry
+	for _, e := range d.entries() {
+		if e.dataEntry != nil {
+			out = append(out, e.dataEntry)
+		}
+	}
+	return out
+}
+
+// datas returns the underlying blob of each of this directory's own
+// leaf entries.
+func (d *Directory) datas() []*resourceBlob {
+	var out []*resourceBlob
+	for _, de := range d.dataEntries() {
+		out = append(out, de.data)
+	}
+	return out
+}
+
+// walk calls fn for d and, recursively, every subdirectory reachable
+// through d's entries, in the same depth-first order section.go's four
+// freeze/WriteTo passes rely on to agree on file layout.
+func (d *Directory) walk(fn func(dir *Directory) error) error {
+	if err := fn(d); err != nil {
+		return err
+	}
+	for _, e := range d.entries() {
+		if e.subdirectory != nil {
+			if err := e.subdirectory.walk(fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addSubdirectory returns the subdirectory registered under name (if
+// non-nil) or id, creating it with the given characteristics if it
+// doesn't exist yet.
+func (d *Directory) addSubdirectory(name *string, id *int, characteristics uint32) (*Directory, error) {
+	if id != nil {
+		for _, e := range d.idEntries {
+			if e.id != nil && *e.id == *id {
+				if e.subdirectory == nil {
+					return nil, errors.Errorf("resource id %d already registered as data, not a subdirectory", *id)
+				}
+				return e.subdirectory, nil
+			}
+		}
+	} else if name != nil {
+		for _, e := range d.nameEntries {
+			if e.name != nil && e.name.string == *name {
+				if e.subdirectory == nil {
+					return nil, errors.Errorf("resource name %q already registered as data, not a subdirectory", *name)
+				}
+				return e.subdirectory, nil
+			}
+		}
+	} else {
+		return nil, errors.New("addSubdirectory: either name or id must be set")
+	}
+
+	subdir := &Directory{characteristics: characteristics}
+	e := &directoryEntry{subdirectory: subdir}
+	if id != nil {
+		v := *id
+		e.id = &v
+		d.idEntries = append(d.idEntries, e)
+	} else {
+		str := &_string{string: *name}
+		e.name = str
+		d.strings = append(d.strings, str)
+		d.nameEntries = append(d.nameEntries, e)
+	}
+	return subdir, nil
+}
+
+// addData registers blob as a leaf entry under name (if non-nil) or id.
+func (d *Directory) addData(name *string, id *int, blob common.Blob) (*DataEntry, error) {
+	de := &DataEntry{data: &resourceBlob{Blob: blob}}
+	e := &directoryEntry{dataEntry: de}
+	switch {
+	case id != nil:
+		v := *id
+		e.id = &v
+		d.idEntries = append(d.idEntries, e)
+	case name != nil:
+		str := &_string{string: *name}
+		e.name = str
+		d.strings = append(d.strings, str)
+		d.nameEntries = append(d.nameEntries, e)
+	default:
+		return nil, errors.New("addData: either name or id must be set")
+	}
+	return de, nil
+}
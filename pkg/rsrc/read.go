@@ -0,0 +1,125 @@
+package rsrc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// rawBlob adapts a byte slice parsed out of an existing .rsrc section to
+// the common.Blob interface expected by Directory.addData, so a parsed
+// resource's data can be fed straight back into the tree being rebuilt.
+type rawBlob struct {
+	r *bytes.Reader
+}
+
+func newRawBlob(b []byte) *rawBlob {
+	return &rawBlob{r: bytes.NewReader(b)}
+}
+
+func (b *rawBlob) Read(p []byte) (int, error) { return b.r.Read(p) }
+func (b *rawBlob) Size() int64                { return b.r.Size() }
+
+// Read parses the raw bytes of an existing .rsrc section (as produced by
+// Section.WriteTo, e.g. after extracting it from a syso via coff.Read)
+// and rebuilds the resource directory tree. The result can be queried
+// with ResourceIDExists/ResourceNameExists, extended with
+// AddResourceByID/AddResourceByName and written back out with WriteTo;
+// Section has no method that lists, removes or replaces a resource.
+func Read(data []byte) (*Section, error) {
+	s := New()
+	seen := make(map[uint32]bool)
+	if err := readDirectory(data, 0, s.rootDir, seen); err != nil {
+		return nil, errors.Wrap(err, "failed to parse .rsrc directory")
+	}
+	return s, nil
+}
+
+func readDirectory(data []byte, offset uint32, dir *Directory, seen map[uint32]bool) error {
+	if seen[offset] {
+		return errors.Errorf("resource directory at offset %d forms a cycle", offset)
+	}
+	seen[offset] = true
+
+	var raw rawDirectory
+	if err := readStruct(data, offset, &raw); err != nil {
+		return errors.Wrap(err, "failed to read resource directory")
+	}
+	dir.characteristics = raw.Characteristics
+
+	entryOffset := offset + uint32(binary.Size(&raw))
+	total := int(raw.NumberOfNameEntries) + int(raw.NumberOfIDEntries)
+	for i := 0; i < total; i++ {
+		var e rawDirectoryEntry
+		if err := readStruct(data, entryOffset, &e); err != nil {
+			return errors.Wrapf(err, "failed to read resource directory entry #%d", i)
+		}
+		entryOffset += uint32(binary.Size(&e))
+
+		var name *string
+		var id *int
+		if e.NameOffsetOrIntegerID&0x80000000 != 0 {
+			str, err := readResourceString(data, e.NameOffsetOrIntegerID&0x7fffffff)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read resource entry #%d name", i)
+			}
+			name = &str
+		} else {
+			v := int(e.NameOffsetOrIntegerID)
+			id = &v
+		}
+
+		child := e.DataEntryOffsetOrSubdirectoryOffset
+		if child&0x80000000 != 0 {
+			subdir, err := dir.addSubdirectory(name, id, 0)
+			if err != nil {
+				return errors.Wrapf(err, "failed to add resource subdirectory #%d", i)
+			}
+			if err := readDirectory(data, child&0x7fffffff, subdir, seen); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var de rawDataEntry
+		if err := readStruct(data, child, &de); err != nil {
+			return errors.Wrapf(err, "failed to read resource data entry #%d", i)
+		}
+		if int(de.DataRVA)+int(de.Size) > len(data) {
+			return errors.Errorf("resource data entry #%d out of bounds", i)
+		}
+		blob := newRawBlob(data[de.DataRVA : de.DataRVA+de.Size])
+		if _, err := dir.addData(name, id, blob); err != nil {
+			return errors.Wrapf(err, "failed to add resource data #%d", i)
+		}
+	}
+
+	return nil
+}
+
+func readResourceString(data []byte, offset uint32) (string, error) {
+	if int(offset)+2 > len(data) {
+		return "", errors.New("resource string length out of bounds")
+	}
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	start := int(offset) + 2
+	end := start + length*2
+	if end > len(data) {
+		return "", errors.New("resource string out of bounds")
+	}
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[start+i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+func readStruct(data []byte, offset uint32, v interface{}) error {
+	size := binary.Size(v)
+	if int(offset)+size > len(data) {
+		return errors.New("read out of bounds")
+	}
+	return binary.Read(bytes.NewReader(data[offset:]), binary.LittleEndian, v)
+}
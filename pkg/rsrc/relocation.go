@@ -0,0 +1,22 @@
+package rsrc
+
+// Relocation is the coff.Relocation implementation for offsets inside a
+// .rsrc section's Data Entries: each resource's DataRVA needs a
+// relocation so the linker can turn its section-relative offset into a
+// real virtual address, resolved against the section's own symbol.
+type Relocation struct {
+	va         uint32
+	symbolName string
+}
+
+// VirtualAddress returns the section-relative offset the relocation
+// applies to.
+func (r *Relocation) VirtualAddress() uint32 {
+	return r.va
+}
+
+// SymbolName returns the name of the symbol the relocation is resolved
+// against: the .rsrc section's own symbol, registered via rsrc.Add.
+func (r *Relocation) SymbolName() string {
+	return r.symbolName
+}
@@ -28,6 +28,19 @@ func (s *Section) Name() string {
 	return ".rsrc"
 }
 
+// Add adds s to f along with the IMAGE_SYM_CLASS_STATIC symbol s's own
+// relocations target, so the resulting file resolves them correctly.
+// Use this instead of f.AddSection(s) directly.
+func Add(f *coff.File, s *Section) error {
+	if err := f.AddSection(s); err != nil {
+		return err
+	}
+	if _, err := f.AddSectionSymbol(s.Name(), s); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Size returns the section's size.
 func (s *Section) Size() int {
 	return int(s.freeze())
@@ -39,6 +52,12 @@ func (s *Section) Relocations() []coff.Relocation {
 	return s.relocations
 }
 
+// Characteristics returns coff.DefaultCharacteristics: .rsrc is
+// read-only initialized data.
+func (s *Section) Characteristics() uint32 {
+	return coff.DefaultCharacteristics
+}
+
 // ResourceIDExists returns true if a resource with given integer id exists.
 func (s *Section) ResourceIDExists(id int) bool {
 	for _, e := range s.rootDir.idEntries {
@@ -153,7 +172,8 @@ func (s *Section) freeze() uint32 {
 		for _, e := range dir.dataEntries() {
 			e.offset = offset
 			s.relocations = append(s.relocations, &Relocation{
-				va: offset,
+				va:         offset,
+				symbolName: s.Name(),
 			})
 			offset += uint32(binary.Size(&rawDataEntry{}))
 		}